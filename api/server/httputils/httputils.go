@@ -0,0 +1,41 @@
+package httputils // import "github.com/docker/docker/api/server/httputils"
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+)
+
+// BoolValue transforms a form value into a boolean. After trimming space
+// and lowering case, "", "0", "no", "false" and "none" (and an absent key)
+// are false; any other value is true.
+func BoolValue(r *http.Request, k string) bool {
+	s := strings.ToLower(strings.TrimSpace(r.FormValue(k)))
+	return !(s == "" || s == "0" || s == "no" || s == "false" || s == "none")
+}
+
+// WriteJSON writes the value v to the http response stream as json with
+// status code.
+func WriteJSON(w http.ResponseWriter, code int, v interface{}) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	return json.NewEncoder(w).Encode(v)
+}
+
+type versionCtxKey struct{}
+
+// VersionFromContext returns the API version negotiated for the request
+// that ctx was derived from, or "" if none was set (e.g. in tests that
+// call daemon methods directly rather than going through the API router).
+func VersionFromContext(ctx context.Context) string {
+	v, _ := ctx.Value(versionCtxKey{}).(string)
+	return v
+}
+
+// WithVersion returns a copy of ctx carrying the given negotiated API
+// version, for use by the router middleware that sets it on incoming
+// requests.
+func WithVersion(ctx context.Context, version string) context.Context {
+	return context.WithValue(ctx, versionCtxKey{}, version)
+}
@@ -0,0 +1,23 @@
+package container // import "github.com/docker/docker/api/server/router/container"
+
+import (
+	"context"
+
+	"github.com/docker/docker/api/types"
+	containertypes "github.com/docker/docker/api/types/container"
+)
+
+// Backend is the subset of the Daemon API that the container router needs
+// to serve /containers/json and the exec resize endpoint.
+type Backend interface {
+	Containers(ctx context.Context, config *containertypes.ListOptions) ([]*containertypes.Summary, error)
+
+	// EncodeNextPageToken returns the opaque cursor that resumes a
+	// Containers query immediately after last, for the
+	// X-Docker-Next-Page-Token response header.
+	EncodeNextPageToken(config *containertypes.ListOptions, last *containertypes.Summary) (string, error)
+
+	// ContainerExecResize changes the size of the TTY of a running exec
+	// process, mirroring ContainerResize for the container's own TTY.
+	ContainerExecResize(ctx context.Context, execID string, options types.ResizeOptions) error
+}
@@ -0,0 +1,30 @@
+package container // import "github.com/docker/docker/api/server/router/container"
+
+import (
+	"github.com/docker/docker/api/server/router"
+)
+
+// containerRouter is a router to talk with the container controller
+type containerRouter struct {
+	backend Backend
+	routes  []router.Route
+}
+
+// NewRouter initializes a new container router
+func NewRouter(b Backend) router.Router {
+	r := &containerRouter{backend: b}
+	r.initRoutes()
+	return r
+}
+
+// Routes returns the available routes to the container controller
+func (r *containerRouter) Routes() []router.Route {
+	return r.routes
+}
+
+func (r *containerRouter) initRoutes() {
+	r.routes = []router.Route{
+		router.NewGetRoute("/containers/json", r.getContainersJSON),
+		router.NewPostRoute("/exec/{id:.*}/resize", r.postContainerExecResize),
+	}
+}
@@ -0,0 +1,32 @@
+package container // import "github.com/docker/docker/api/server/router/container"
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/errdefs"
+)
+
+// postContainerExecResize changes the size of the TTY of an exec process,
+// mirroring postContainersResize for the container's own primary TTY.
+func (r *containerRouter) postContainerExecResize(ctx context.Context, w http.ResponseWriter, req *http.Request, vars map[string]string) error {
+	if err := req.ParseForm(); err != nil {
+		return err
+	}
+
+	height, err := strconv.Atoi(req.Form.Get("h"))
+	if err != nil {
+		return errdefs.InvalidParameter(err)
+	}
+	width, err := strconv.Atoi(req.Form.Get("w"))
+	if err != nil {
+		return errdefs.InvalidParameter(err)
+	}
+
+	return r.backend.ContainerExecResize(ctx, vars["id"], types.ResizeOptions{
+		Height: uint(height),
+		Width:  uint(width),
+	})
+}
@@ -0,0 +1,122 @@
+package container // import "github.com/docker/docker/api/server/router/container"
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/docker/docker/api/server/httputils"
+	containertypes "github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/api/types/versions"
+	"github.com/docker/docker/errdefs"
+)
+
+// nextPageTokenHeader carries the opaque cursor to resume a paginated
+// /containers/json query after its last result, once the caller has asked
+// for a Sort/Limit the View DB can apply server-side.
+const nextPageTokenHeader = "X-Docker-Next-Page-Token"
+
+// pageTokenMinVersion is the first API version that understands page_token
+// / X-Docker-Next-Page-Token; it must match daemon.sortMinVersion, since the
+// daemon already strips PageToken from requests below this version.
+const pageTokenMinVersion = "1.48"
+
+func (r *containerRouter) getContainersJSON(ctx context.Context, w http.ResponseWriter, req *http.Request, vars map[string]string) error {
+	if err := req.ParseForm(); err != nil {
+		return err
+	}
+
+	filterArgs, err := filters.FromJSON(req.Form.Get("filters"))
+	if err != nil {
+		return err
+	}
+
+	config := &containertypes.ListOptions{
+		All:       httputils.BoolValue(req, "all"),
+		Size:      httputils.BoolValue(req, "size"),
+		Latest:    httputils.BoolValue(req, "latest"),
+		Since:     req.Form.Get("since"),
+		Before:    req.Form.Get("before"),
+		Filters:   filterArgs,
+		PageToken: req.Form.Get("page_token"),
+	}
+
+	if tmpLimit := req.Form.Get("limit"); tmpLimit != "" {
+		limit, err := strconv.Atoi(tmpLimit)
+		if err != nil {
+			return err
+		}
+		config.Limit = limit
+	}
+
+	if tmpSort := req.Form.Get("sort"); tmpSort != "" {
+		sortBy, err := parseSortQueryParam(tmpSort)
+		if err != nil {
+			return err
+		}
+		config.Sort = sortBy
+	}
+
+	// Ask for one more than requested so we know, without a second round
+	// trip, whether there's a further page to point NextPageToken at.
+	lookaheadConfig := *config
+	if config.Limit > 0 {
+		lookaheadConfig.Limit = config.Limit + 1
+	}
+
+	containers, err := r.backend.Containers(ctx, &lookaheadConfig)
+	if err != nil {
+		return err
+	}
+
+	pageTokenAllowed := versions.GreaterThanOrEqualTo(httputils.VersionFromContext(ctx), pageTokenMinVersion)
+	if config.Limit > 0 && len(containers) > config.Limit {
+		if pageTokenAllowed {
+			nextToken, err := r.backend.EncodeNextPageToken(config, containers[config.Limit-1])
+			if err != nil {
+				return err
+			}
+			w.Header().Set(nextPageTokenHeader, nextToken)
+		}
+		containers = containers[:config.Limit]
+	}
+
+	return httputils.WriteJSON(w, http.StatusOK, containers)
+}
+
+// parseSortQueryParam parses the "key:asc,key2:desc" form of the sort query
+// parameter into the SortOption slice Daemon.Containers expects. Only the
+// trailing ":asc"/":desc" is split off, since a key itself can contain a
+// colon ("label:com.example.tier", "annotation:io.kubernetes.docker.type").
+func parseSortQueryParam(raw string) ([]containertypes.SortOption, error) {
+	parts := strings.Split(raw, ",")
+	sortBy := make([]containertypes.SortOption, 0, len(parts))
+	for _, part := range parts {
+		key, desc := part, false
+		switch {
+		case strings.HasSuffix(part, ":desc"):
+			key, desc = strings.TrimSuffix(part, ":desc"), true
+		case strings.HasSuffix(part, ":asc"):
+			key = strings.TrimSuffix(part, ":asc")
+		}
+		if !isValidSortKey(key) {
+			return nil, errdefs.InvalidParameter(fmt.Errorf("invalid sort key %q", key))
+		}
+		sortBy = append(sortBy, containertypes.SortOption{Key: key, Desc: desc})
+	}
+	return sortBy, nil
+}
+
+// isValidSortKey reports whether key is one Daemon.Containers knows how to
+// sort by, so that a typo (or a key this daemon version doesn't support
+// yet) is rejected up front instead of silently sorting as a no-op.
+func isValidSortKey(key string) bool {
+	switch key {
+	case "created", "name", "state", "image", "size":
+		return true
+	}
+	return strings.HasPrefix(key, "label:") || strings.HasPrefix(key, "annotation:")
+}
@@ -0,0 +1,24 @@
+package container // import "github.com/docker/docker/api/server/router/container"
+
+import (
+	"testing"
+
+	containertypes "github.com/docker/docker/api/types/container"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+func TestParseSortQueryParam(t *testing.T) {
+	sortBy, err := parseSortQueryParam("label:com.example.tier:asc,annotation:io.kubernetes.docker.type:desc,created")
+	assert.NilError(t, err)
+	assert.Assert(t, is.DeepEqual(sortBy, []containertypes.SortOption{
+		{Key: "label:com.example.tier", Desc: false},
+		{Key: "annotation:io.kubernetes.docker.type", Desc: true},
+		{Key: "created", Desc: false},
+	}))
+}
+
+func TestParseSortQueryParamInvalidKey(t *testing.T) {
+	_, err := parseSortQueryParam("not-a-real-key:asc")
+	assert.Assert(t, is.ErrorContains(err, "invalid sort key"))
+}
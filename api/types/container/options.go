@@ -0,0 +1,37 @@
+package container // import "github.com/docker/docker/api/types/container"
+
+import "github.com/docker/docker/api/types/filters"
+
+// ListOptions holds parameters to list containers with the ContainerList
+// API.
+type ListOptions struct {
+	Size    bool
+	All     bool
+	Latest  bool
+	Since   string
+	Before  string
+	Limit   int
+	Filters filters.Args
+
+	// Sort requests that the result be server-side sorted on one or more
+	// keys before Limit is applied, instead of the client pulling the
+	// full list and sorting it locally. Keys are applied in order, with
+	// ties broken by the next key and, ultimately, by container ID.
+	Sort []SortOption
+
+	// PageToken resumes a previous Sort/Limit query immediately after the
+	// last container it returned, using the opaque cursor handed back as
+	// NextPageToken. It is meaningless without a stable Sort (or the
+	// default ordering Limit already implies) and is only valid against
+	// the daemon that issued it.
+	PageToken string
+}
+
+// SortOption describes a single key to sort a container list by.
+type SortOption struct {
+	// Key is one of "created", "name", "state", "image", "size", or a
+	// reference into a container's labels ("label:com.example.tier") or
+	// annotations ("annotation:io.kubernetes.docker.type").
+	Key string
+	Desc bool
+}
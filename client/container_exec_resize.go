@@ -0,0 +1,22 @@
+package client // import "github.com/docker/docker/client"
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+
+	"github.com/docker/docker/api/types"
+)
+
+// ContainerExecResize changes the size of the TTY of an exec process
+// running in a container, mirroring ContainerResize for the container's
+// own primary TTY.
+func (cli *Client) ContainerExecResize(ctx context.Context, execID string, options types.ResizeOptions) error {
+	query := url.Values{}
+	query.Set("h", strconv.Itoa(int(options.Height)))
+	query.Set("w", strconv.Itoa(int(options.Width)))
+
+	resp, err := cli.postRaw(ctx, "/exec/"+execID+"/resize", query, nil, nil)
+	defer ensureReaderClosed(resp)
+	return err
+}
@@ -0,0 +1,104 @@
+package client // import "github.com/docker/docker/client"
+
+import (
+	"context"
+	"encoding/json"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/api/types/versions"
+)
+
+// nextPageTokenHeader mirrors the constant of the same name in
+// api/server/router/container.
+const nextPageTokenHeader = "X-Docker-Next-Page-Token"
+
+// ContainerList returns the list of containers in the docker host.
+func (cli *Client) ContainerList(ctx context.Context, options container.ListOptions) ([]container.Summary, error) {
+	containers, _, err := cli.containerList(ctx, options)
+	return containers, err
+}
+
+// ContainerListPage is like ContainerList, but also returns the opaque
+// token to pass as ListOptions.PageToken on the next call. The token is
+// empty once there's nothing left to page through.
+func (cli *Client) ContainerListPage(ctx context.Context, options container.ListOptions) ([]container.Summary, string, error) {
+	return cli.containerList(ctx, options)
+}
+
+func (cli *Client) containerList(ctx context.Context, options container.ListOptions) ([]container.Summary, string, error) {
+	query := url.Values{}
+
+	if options.All {
+		query.Set("all", "1")
+	}
+
+	if options.Limit > 0 {
+		query.Set("limit", strconv.Itoa(options.Limit))
+	}
+
+	if options.Latest {
+		query.Set("latest", "1")
+	}
+
+	if options.Since != "" {
+		query.Set("since", options.Since)
+	}
+
+	if options.Before != "" {
+		query.Set("before", options.Before)
+	}
+
+	if options.Size {
+		query.Set("size", "1")
+	}
+
+	if options.Filters.Len() > 0 {
+		filterJSON, err := filters.ToJSON(options.Filters)
+		if err != nil {
+			return nil, "", err
+		}
+		query.Set("filters", filterJSON)
+	}
+
+	// Sort and PageToken are only sent when the client's API version is
+	// 1.48 or later; below that they are left out of the request without
+	// an error, so the list is neither sorted nor resumed from a token.
+	if versions.GreaterThanOrEqualTo(cli.ClientVersion(), "1.48") {
+		if len(options.Sort) > 0 {
+			query.Set("sort", encodeSortOptions(options.Sort))
+		}
+		if options.PageToken != "" {
+			query.Set("page_token", options.PageToken)
+		}
+	}
+
+	resp, err := cli.get(ctx, "/containers/json", query, nil)
+	defer ensureReaderClosed(resp)
+	if err != nil {
+		return nil, "", err
+	}
+
+	var containers []container.Summary
+	if err := json.NewDecoder(resp.Body).Decode(&containers); err != nil {
+		return nil, "", err
+	}
+	return containers, resp.Header.Get(nextPageTokenHeader), nil
+}
+
+// encodeSortOptions renders Sort as the "key:asc,key2:desc" query parameter
+// understood by the daemon.
+func encodeSortOptions(sortBy []container.SortOption) string {
+	parts := make([]string, 0, len(sortBy))
+	for _, opt := range sortBy {
+		dir := "asc"
+		if opt.Desc {
+			dir = "desc"
+		}
+		parts = append(parts, opt.Key+":"+dir)
+	}
+	return strings.Join(parts, ",")
+}
@@ -0,0 +1,25 @@
+package daemon // import "github.com/docker/docker/daemon"
+
+import (
+	"context"
+	"errors"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/errdefs"
+)
+
+// ContainerExecResize changes the size of the TTY of an exec process
+// running in a container, forwarding TIOCSWINSZ to it via containerd, the
+// same way ContainerResize does for the container's own primary TTY.
+func (daemon *Daemon) ContainerExecResize(ctx context.Context, execID string, options types.ResizeOptions) error {
+	ec, err := daemon.getExecConfig(execID)
+	if err != nil {
+		return errdefs.NotFound(err)
+	}
+
+	if ec.Process == nil {
+		return errdefs.Conflict(errors.New("exec process " + execID + " is not running"))
+	}
+
+	return ec.Process.Resize(ctx, options.Width, options.Height)
+}
@@ -0,0 +1,330 @@
+package daemon // import "github.com/docker/docker/daemon"
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/docker/docker/api/server/httputils"
+	containertypes "github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/api/types/versions"
+	"github.com/docker/docker/container"
+	"github.com/docker/docker/errdefs"
+)
+
+// annotationFilterMinVersion is the first API version that surfaces
+// container annotations (see Summary.HostConfig.Annotations), so it's also
+// the first version allowed to filter by them; older clients have annotation
+// filters silently ignored rather than getting "invalid filter".
+const annotationFilterMinVersion = "1.46"
+
+// sortMinVersion is the first API version that understands server-side
+// Sort and PageToken/NextPageToken pagination. Older clients have both
+// silently ignored rather than honored, the same way annotationFilterMinVersion
+// is handled, so that an old client issuing ?sort=... against a new daemon
+// doesn't get a response whose shape it never asked for and can't rely on.
+const sortMinVersion = "1.48"
+
+// acceptedListFilterTags lists the filter keys accepted by Daemon.Containers.
+// Every key here must have a matching case in includeContainerInList: the
+// container.Snapshot this reducer works from doesn't carry image ancestry,
+// health, network, port, or mount data, so filters like "ancestor" or
+// "health" can't be honored yet and aren't advertised as accepted until
+// they are.
+var acceptedListFilterTags = map[string]bool{
+	"annotation": true,
+	"id":         true,
+	"label":      true,
+	"name":       true,
+}
+
+// defaultSort is applied when the caller doesn't request an explicit Sort,
+// but Limit (or Latest) still needs a well-defined "most recent" ordering.
+var defaultSort = []containertypes.SortOption{{Key: "created", Desc: true}}
+
+// Containers returns the list of containers to show given the user's
+// filtering options.
+func (daemon *Daemon) Containers(ctx context.Context, config *containertypes.ListOptions) ([]*containertypes.Summary, error) {
+	return daemon.reduceContainers(ctx, config)
+}
+
+func (daemon *Daemon) reduceContainers(ctx context.Context, config *containertypes.ListOptions) ([]*containertypes.Summary, error) {
+	apiVersion := httputils.VersionFromContext(ctx)
+	annotationFilterAllowed := apiVersion == "" || versions.GreaterThanOrEqualTo(apiVersion, annotationFilterMinVersion)
+	sortAllowed := apiVersion == "" || versions.GreaterThanOrEqualTo(apiVersion, sortMinVersion)
+
+	if !sortAllowed {
+		// Silently ignored, not rejected: an older client was never told
+		// about Sort/PageToken, so it can't have sent either on purpose.
+		config.Sort = nil
+		config.PageToken = ""
+	}
+
+	if sortUsesSize(config.Sort) && !config.Size {
+		// Unlike an unsupported version, this one is the caller's mistake
+		// to fix: SizeRw/SizeRootFs are only computed when Size is
+		// requested, so sorting by "size" without it would silently
+		// collapse to the ID tiebreaker instead of sorting by size at all.
+		return nil, errdefs.InvalidParameter(errors.New("sort key \"size\" requires the size option to be set"))
+	}
+
+	if err := daemon.foldFilter(config, annotationFilterAllowed); err != nil {
+		return nil, err
+	}
+
+	if config.Latest {
+		config.Limit = 1
+	}
+
+	view := daemon.containersReplica.Snapshot()
+
+	var sinceCreated, beforeCreated int64
+	var hasSince, hasBefore bool
+	if config.Since != "" {
+		sinceCreated, hasSince = referenceCreated(view, config.Since)
+		if !hasSince {
+			return nil, errdefs.InvalidParameter(fmt.Errorf("no such container: %s", config.Since))
+		}
+	}
+	if config.Before != "" {
+		beforeCreated, hasBefore = referenceCreated(view, config.Before)
+		if !hasBefore {
+			return nil, errdefs.InvalidParameter(fmt.Errorf("no such container: %s", config.Before))
+		}
+	}
+
+	containers := []*containertypes.Summary{}
+	for _, c := range view {
+		if hasSince && c.Created <= sinceCreated {
+			continue
+		}
+		if hasBefore && c.Created >= beforeCreated {
+			continue
+		}
+		keep, err := daemon.includeContainerInList(c, config, annotationFilterAllowed)
+		if err != nil {
+			return nil, err
+		}
+		if !keep {
+			continue
+		}
+		containers = append(containers, c.newContainerSummary())
+	}
+
+	// Sort after filtering but before Limit, so "the 20 largest running
+	// containers" can be served in one round trip. When the caller asks
+	// for a Limit without an explicit Sort, fall back to newest-first so
+	// that "most recent" has one unambiguous meaning.
+	switch {
+	case len(config.Sort) > 0:
+		sortContainers(containers, config.Sort)
+	case config.Limit > 0:
+		sortContainers(containers, defaultSort)
+	}
+
+	// Known limitation: each page re-filters and re-sorts the entire
+	// containersReplica snapshot, then scans to the cursor, rather than
+	// resuming an ordered View DB scan directly at the cursor. Walking the
+	// full set in pages of L costs O(N log N) per page instead of O(L).
+	// containersReplica doesn't expose a range scan keyed by an arbitrary
+	// (possibly derived, e.g. label:/annotation:) sort key, so doing better
+	// would need that index built first; until then, this trades per-page
+	// cost for implementation simplicity.
+	if config.PageToken != "" {
+		cur, err := decodePageToken(config.PageToken)
+		if err != nil {
+			return nil, errdefs.InvalidParameter(err)
+		}
+		containers = resumeAfterCursor(containers, primarySort(config), cur)
+	}
+
+	if config.Limit > 0 && len(containers) > config.Limit {
+		containers = containers[:config.Limit]
+	}
+
+	return containers, nil
+}
+
+// sortContainers orders containers by the requested keys, in order, with
+// ties broken by the next key and, ultimately, by container ID. The ID
+// tiebreaker is always applied, even for an explicit Sort, so that the
+// result is fully deterministic regardless of map/b-tree iteration order;
+// this is what lets cursor-based pagination resume without duplicates or
+// gaps.
+func sortContainers(containers []*containertypes.Summary, sortBy []containertypes.SortOption) {
+	sort.SliceStable(containers, func(i, j int) bool {
+		for _, opt := range sortBy {
+			cmp := compareSortKey(containers[i], containers[j], opt.Key)
+			if cmp == 0 {
+				continue
+			}
+			if opt.Desc {
+				return cmp > 0
+			}
+			return cmp < 0
+		}
+		return containers[i].ID < containers[j].ID
+	})
+}
+
+// sortUsesSize reports whether sortBy sorts by "size", which is only
+// meaningful once SizeRw/SizeRootFs have actually been computed.
+func sortUsesSize(sortBy []containertypes.SortOption) bool {
+	for _, opt := range sortBy {
+		if opt.Key == "size" {
+			return true
+		}
+	}
+	return false
+}
+
+func compareSortKey(a, b *containertypes.Summary, key string) int {
+	switch {
+	case key == "created":
+		return compareInt64(a.Created, b.Created)
+	case key == "name":
+		return strings.Compare(primaryName(a), primaryName(b))
+	case key == "state":
+		return strings.Compare(a.State, b.State)
+	case key == "image":
+		return strings.Compare(a.Image, b.Image)
+	case key == "size":
+		return compareInt64(a.SizeRw, b.SizeRw)
+	case strings.HasPrefix(key, "label:"):
+		k := strings.TrimPrefix(key, "label:")
+		return strings.Compare(a.Labels[k], b.Labels[k])
+	case strings.HasPrefix(key, "annotation:"):
+		k := strings.TrimPrefix(key, "annotation:")
+		return strings.Compare(annotationValue(a, k), annotationValue(b, k))
+	default:
+		return 0
+	}
+}
+
+func annotationValue(c *containertypes.Summary, key string) string {
+	if c.HostConfig == nil {
+		return ""
+	}
+	return c.HostConfig.Annotations[key]
+}
+
+func compareInt64(a, b int64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
+
+func primaryName(c *containertypes.Summary) string {
+	if len(c.Names) == 0 {
+		return ""
+	}
+	return strings.TrimPrefix(c.Names[0], "/")
+}
+
+// foldFilter validates the filters requested by the caller and folds them
+// into a form that's cheap to evaluate per-container.
+func (daemon *Daemon) foldFilter(config *containertypes.ListOptions, annotationFilterAllowed bool) error {
+	if config.Filters.Len() == 0 {
+		return nil
+	}
+	if !annotationFilterAllowed && len(config.Filters.Get("annotation")) > 0 {
+		config.Filters.Del("annotation", config.Filters.Get("annotation")...)
+	}
+	return config.Filters.Validate(acceptedListFilterTags)
+}
+
+// includeContainerInList decides whether a container matches the filters
+// requested in config.
+func (daemon *Daemon) includeContainerInList(c *container.Snapshot, config *containertypes.ListOptions, annotationFilterAllowed bool) (bool, error) {
+	if !config.All && !c.Running {
+		return false, nil
+	}
+
+	if ids := config.Filters.Get("id"); len(ids) > 0 {
+		if !matchesIDFilter(c.ID, ids) {
+			return false, nil
+		}
+	}
+
+	if names := config.Filters.Get("name"); len(names) > 0 {
+		if !matchesNameFilter(c.Names, names) {
+			return false, nil
+		}
+	}
+
+	if config.Filters.Contains("label") && !config.Filters.MatchKVList("label", c.Labels) {
+		return false, nil
+	}
+
+	if annotationFilterAllowed && config.Filters.Contains("annotation") {
+		var annotations map[string]string
+		if c.HostConfig != nil {
+			annotations = c.HostConfig.Annotations
+		}
+		if !config.Filters.MatchKVList("annotation", annotations) {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+// referenceCreated resolves the "since"/"before" reference argument (a
+// container ID or name) against the snapshot view, returning the Created
+// time of the matching container. It reports false if idOrName doesn't
+// match any container currently in view.
+func referenceCreated(view []*container.Snapshot, idOrName string) (int64, bool) {
+	trimmedRef := strings.TrimPrefix(idOrName, "/")
+	for _, c := range view {
+		if c.ID == idOrName {
+			return c.Created, true
+		}
+		for _, name := range c.Names {
+			if strings.TrimPrefix(name, "/") == trimmedRef {
+				return c.Created, true
+			}
+		}
+	}
+	return 0, false
+}
+
+// matchesIDFilter returns true if id has any of the requested filters as a
+// prefix, so a truncated ID (as returned by `docker ps`) can be used to
+// filter as well as the full one.
+func matchesIDFilter(id string, filters []string) bool {
+	for _, filter := range filters {
+		if strings.HasPrefix(id, filter) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesNameFilter returns true if any of the container's names matches
+// any of the requested name filters. Names and filters are compared with
+// their leading slash stripped so that both "^a" and "^/a" behave the same
+// way (moby/moby#37453).
+func matchesNameFilter(names []string, filters []string) bool {
+	for _, name := range names {
+		trimmedName := strings.TrimPrefix(name, "/")
+		for _, filter := range filters {
+			trimmedFilter := strings.TrimPrefix(filter, "/")
+			if trimmedName == trimmedFilter {
+				return true
+			}
+			if re, err := regexp.Compile(trimmedFilter); err == nil && re.MatchString(trimmedName) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,167 @@
+package daemon // import "github.com/docker/docker/daemon"
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	containertypes "github.com/docker/docker/api/types/container"
+)
+
+// pageCursor is the decoded form of a PageToken / NextPageToken: the sort
+// key value and container ID of the last element returned, which is enough
+// to resume immediately after it under the same sort.
+type pageCursor struct {
+	SortKey string `json:"k"`
+	ID      string `json:"i"`
+}
+
+// pageTokenKey is generated once per daemon process. Restarting the daemon
+// invalidates every outstanding page token, rather than risk resuming a
+// query against a View DB that no longer matches what produced the token.
+var pageTokenKey = sync.OnceValue(func() []byte {
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		// crypto/rand failing means the platform's RNG is broken; nothing
+		// downstream of this can be trusted either.
+		panic(err)
+	}
+	return key
+})
+
+func encodePageToken(cur pageCursor) (string, error) {
+	payload, err := json.Marshal(cur)
+	if err != nil {
+		return "", err
+	}
+	mac := hmac.New(sha256.New, pageTokenKey())
+	mac.Write(payload)
+	sig := mac.Sum(nil)
+	return base64.RawURLEncoding.EncodeToString(payload) + "." + base64.RawURLEncoding.EncodeToString(sig), nil
+}
+
+func decodePageToken(token string) (pageCursor, error) {
+	var cur pageCursor
+
+	payloadPart, sigPart, ok := strings.Cut(token, ".")
+	if !ok {
+		return cur, errors.New("malformed page token")
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(payloadPart)
+	if err != nil {
+		return cur, errors.New("malformed page token")
+	}
+	sig, err := base64.RawURLEncoding.DecodeString(sigPart)
+	if err != nil {
+		return cur, errors.New("malformed page token")
+	}
+
+	mac := hmac.New(sha256.New, pageTokenKey())
+	mac.Write(payload)
+	if !hmac.Equal(sig, mac.Sum(nil)) {
+		return cur, errors.New("invalid or expired page token")
+	}
+	if err := json.Unmarshal(payload, &cur); err != nil {
+		return cur, errors.New("malformed page token")
+	}
+	return cur, nil
+}
+
+// EncodeNextPageToken returns the opaque, signed token that resumes a
+// Containers query immediately after last, under the sort config
+// specifies. It's exposed for the API router to set as the
+// X-Docker-Next-Page-Token response header.
+func EncodeNextPageToken(config *containertypes.ListOptions, last *containertypes.Summary) (string, error) {
+	primary := primarySort(config)
+	return encodePageToken(pageCursor{
+		SortKey: sortKeyValue(last, primary.Key),
+		ID:      last.ID,
+	})
+}
+
+// EncodeNextPageToken is the Backend-facing form of the package-level
+// helper of the same name, so API routers only need to depend on the
+// narrow Backend interface rather than the daemon package itself.
+func (daemon *Daemon) EncodeNextPageToken(config *containertypes.ListOptions, last *containertypes.Summary) (string, error) {
+	return EncodeNextPageToken(config, last)
+}
+
+// resumeAfterCursor returns the first element of containers (already sorted
+// under primary, same as when the cursor was minted) that sorts strictly
+// after cur, and everything after it. Resume is positional rather than an
+// exact-match lookup: if the anchor container itself was removed between
+// pages (expected churn on a busy host with thousands of containers), this
+// still lands on the first container that would have come after it,
+// instead of failing to find an exact match and truncating the rest of
+// the list.
+func resumeAfterCursor(containers []*containertypes.Summary, primary containertypes.SortOption, cur pageCursor) []*containertypes.Summary {
+	for i, c := range containers {
+		if isAfterCursor(c, primary, cur) {
+			return containers[i:]
+		}
+	}
+	return nil
+}
+
+// isAfterCursor reports whether c sorts strictly after cur, using the same
+// (primary key, ID ascending) ordering sortContainers produces. c need not
+// be the exact anchor the cursor was minted from.
+func isAfterCursor(c *containertypes.Summary, primary containertypes.SortOption, cur pageCursor) bool {
+	cmp := strings.Compare(sortKeyValue(c, primary.Key), cur.SortKey)
+	if cmp == 0 {
+		return c.ID > cur.ID
+	}
+	if primary.Desc {
+		return cmp < 0
+	}
+	return cmp > 0
+}
+
+// primarySort returns the sort key a page token is anchored to: the first
+// explicit Sort option, or the implicit defaultSort Limit/Latest fall back
+// on.
+func primarySort(config *containertypes.ListOptions) containertypes.SortOption {
+	if len(config.Sort) > 0 {
+		return config.Sort[0]
+	}
+	return defaultSort[0]
+}
+
+// sortKeyValue renders a container's value for key as a string, for
+// inclusion in (and lexical comparison against) a page token. Numeric keys
+// are zero-padded so that lexical order agrees with numeric order, since
+// resumeAfterCursor compares these strings positionally, not just for
+// equality. Created/SizeRw are never negative, so padding is all that's
+// needed; there's no sign to account for.
+func sortKeyValue(c *containertypes.Summary, key string) string {
+	switch {
+	case key == "created":
+		return formatSortableInt64(c.Created)
+	case key == "name":
+		return primaryName(c)
+	case key == "state":
+		return c.State
+	case key == "image":
+		return c.Image
+	case key == "size":
+		return formatSortableInt64(c.SizeRw)
+	case strings.HasPrefix(key, "label:"):
+		return c.Labels[strings.TrimPrefix(key, "label:")]
+	case strings.HasPrefix(key, "annotation:"):
+		return annotationValue(c, strings.TrimPrefix(key, "annotation:"))
+	default:
+		return ""
+	}
+}
+
+// formatSortableInt64 zero-pads n to the width of math.MaxInt64 so that
+// strings.Compare on the result agrees with numeric comparison of n.
+func formatSortableInt64(n int64) string {
+	return fmt.Sprintf("%019d", n)
+}
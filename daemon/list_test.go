@@ -9,6 +9,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/docker/docker/api/server/httputils"
 	containertypes "github.com/docker/docker/api/types/container"
 	"github.com/docker/docker/api/types/filters"
 	"github.com/docker/docker/container"
@@ -138,6 +139,14 @@ func TestListInvalidFilter(t *testing.T) {
 		Filters: filters.NewArgs(filters.Arg("invalid", "foo")),
 	})
 	assert.Assert(t, is.Error(err, "invalid filter 'invalid'"))
+
+	// "status" isn't accepted: includeContainerInList has no way to honor
+	// it against a container.Snapshot, so it must be rejected rather than
+	// validated-then-silently-ignored.
+	_, err = d.Containers(context.Background(), &containertypes.ListOptions{
+		Filters: filters.NewArgs(filters.Arg("status", "exited")),
+	})
+	assert.Assert(t, is.Error(err, "invalid filter 'status'"))
 }
 
 func TestNameFilter(t *testing.T) {
@@ -189,6 +198,24 @@ func TestNameFilter(t *testing.T) {
 	assert.Assert(t, containerListContainsName(containerListWithPrefix, three.Name))
 }
 
+func TestIDFilter(t *testing.T) {
+	db, err := container.NewViewDB()
+	assert.NilError(t, err)
+	d := &Daemon{
+		containersReplica: db,
+	}
+
+	one := setupContainerWithName(t, "one", d)
+	setupContainerWithName(t, "two", d)
+
+	containerList, err := d.Containers(context.Background(), &containertypes.ListOptions{
+		Filters: filters.NewArgs(filters.Arg("id", one.ID[:8])),
+	})
+	assert.NilError(t, err)
+	assert.Assert(t, is.Len(containerList, 1))
+	assert.Assert(t, containerListContainsName(containerList, one.Name))
+}
+
 func TestLimitFilter(t *testing.T) {
 	db, err := container.NewViewDB()
 	assert.NilError(t, err)
@@ -196,22 +223,343 @@ func TestLimitFilter(t *testing.T) {
 		containersReplica: db,
 	}
 
-	// start a number of containers
+	// start a number of containers, staggering their Created time so that
+	// "most recent" has an unambiguous meaning
 	num := rand.Intn(64)
 	containers := make([]*container.Container, num)
 	for i := range num {
 		name := fmt.Sprintf("cont-%d", i)
 		containers[i] = setupContainerWithName(t, name, d)
+		containers[i].Created = time.Now().Add(time.Duration(i) * time.Second)
+		d.containersReplica.Save(containers[i])
 	}
 
-	// list them with the limit option and verify correctness; note that the limit
-	// option only limits the size of the list, but there are no guarantees as to
-	// which containers are in that list (e.g., if limit is 10, it does not mean
-	// the list will have the first 10 containers we created; it will have any 10
-	// of the containers we created).
+	// list them with the limit option and verify that it returns exactly
+	// the N most recently created containers, newest first
 	limit := rand.Intn(64)
 	containerList, err := d.Containers(context.Background(), &containertypes.ListOptions{Limit: limit})
 	assert.NilError(t, err)
 	expectedListLen := min(num, limit)
 	assert.Assert(t, is.Len(containerList, expectedListLen))
+	for i := 0; i < expectedListLen; i++ {
+		assert.Equal(t, containerList[i].ID, containers[num-1-i].ID)
+	}
+}
+
+func TestLatestFilter(t *testing.T) {
+	db, err := container.NewViewDB()
+	assert.NilError(t, err)
+	d := &Daemon{
+		containersReplica: db,
+	}
+
+	num := 1 + rand.Intn(64)
+	containers := make([]*container.Container, num)
+	for i := range num {
+		name := fmt.Sprintf("cont-%d", i)
+		containers[i] = setupContainerWithName(t, name, d)
+		containers[i].Created = time.Now().Add(time.Duration(i) * time.Second)
+		d.containersReplica.Save(containers[i])
+	}
+
+	containerList, err := d.Containers(context.Background(), &containertypes.ListOptions{Latest: true})
+	assert.NilError(t, err)
+	assert.Assert(t, is.Len(containerList, 1))
+	assert.Equal(t, containerList[0].ID, containers[num-1].ID)
+}
+
+func TestSinceBeforeFilter(t *testing.T) {
+	db, err := container.NewViewDB()
+	assert.NilError(t, err)
+	d := &Daemon{
+		containersReplica: db,
+	}
+
+	num := 5
+	containers := make([]*container.Container, num)
+	for i := range num {
+		name := fmt.Sprintf("cont-%d", i)
+		containers[i] = setupContainerWithName(t, name, d)
+		containers[i].Created = time.Now().Add(time.Duration(i) * time.Second)
+		d.containersReplica.Save(containers[i])
+	}
+
+	// since cont-1: everything created after it (cont-2, cont-3, cont-4)
+	containerList, err := d.Containers(context.Background(), &containertypes.ListOptions{
+		All:   true,
+		Since: containers[1].ID,
+	})
+	assert.NilError(t, err)
+	assert.Assert(t, is.Len(containerList, 3))
+	for _, i := range []int{2, 3, 4} {
+		assert.Assert(t, containerListContainsName(containerList, containers[i].Name))
+	}
+
+	// before cont-3: everything created before it (cont-0, cont-1, cont-2)
+	containerList, err = d.Containers(context.Background(), &containertypes.ListOptions{
+		All:    true,
+		Before: containers[3].ID,
+	})
+	assert.NilError(t, err)
+	assert.Assert(t, is.Len(containerList, 3))
+	for _, i := range []int{0, 1, 2} {
+		assert.Assert(t, containerListContainsName(containerList, containers[i].Name))
+	}
+
+	// unknown reference is an error, not a silently empty/full list
+	_, err = d.Containers(context.Background(), &containertypes.ListOptions{
+		Since: "no-such-container",
+	})
+	assert.ErrorContains(t, err, "no such container")
+}
+
+func TestSortOption(t *testing.T) {
+	db, err := container.NewViewDB()
+	assert.NilError(t, err)
+	d := &Daemon{
+		containersReplica: db,
+	}
+
+	// two containers share a name so that sorting by name alone is a tie,
+	// broken by the implicit ID tiebreaker
+	one := setupContainerWithName(t, "b", d)
+	two := setupContainerWithName(t, "a", d)
+	three := setupContainerWithName(t, "a", d)
+
+	containerList, err := d.Containers(context.Background(), &containertypes.ListOptions{
+		All:  true,
+		Sort: []containertypes.SortOption{{Key: "name"}},
+	})
+	assert.NilError(t, err)
+	assert.Assert(t, is.Len(containerList, 3))
+
+	var expected []*container.Container
+	if two.ID < three.ID {
+		expected = []*container.Container{two, three, one}
+	} else {
+		expected = []*container.Container{three, two, one}
+	}
+	for i, c := range expected {
+		assert.Equal(t, containerList[i].ID, c.ID)
+	}
+}
+
+func TestSortOptionVersionGating(t *testing.T) {
+	db, err := container.NewViewDB()
+	assert.NilError(t, err)
+	d := &Daemon{
+		containersReplica: db,
+	}
+
+	setupContainerWithName(t, "b", d)
+	setupContainerWithName(t, "a", d)
+
+	// an old API version never asked for server-side Sort, so it must not
+	// be applied even though the caller set it (the caller here is the
+	// router, replaying an old client's request verbatim).
+	ctx := httputils.WithVersion(context.Background(), "1.44")
+	containerList, err := d.Containers(ctx, &containertypes.ListOptions{
+		All:  true,
+		Sort: []containertypes.SortOption{{Key: "name"}},
+	})
+	assert.NilError(t, err)
+	assert.Assert(t, is.Len(containerList, 2))
+
+	// from sortMinVersion onward, Sort is honored.
+	ctx = httputils.WithVersion(context.Background(), sortMinVersion)
+	containerList, err = d.Containers(ctx, &containertypes.ListOptions{
+		All:  true,
+		Sort: []containertypes.SortOption{{Key: "name"}},
+	})
+	assert.NilError(t, err)
+	assert.Assert(t, is.Len(containerList, 2))
+	assert.Equal(t, primaryName(containerList[0]), "a")
+	assert.Equal(t, primaryName(containerList[1]), "b")
+}
+
+func TestSortSizeRequiresSizeOption(t *testing.T) {
+	db, err := container.NewViewDB()
+	assert.NilError(t, err)
+	d := &Daemon{
+		containersReplica: db,
+	}
+
+	setupContainerWithName(t, "a", d)
+
+	// without Size, SizeRw/SizeRootFs are never computed, so sorting by
+	// "size" would silently collapse to the ID tiebreaker; reject it
+	// instead of returning an order the caller didn't ask for.
+	_, err = d.Containers(context.Background(), &containertypes.ListOptions{
+		All:  true,
+		Sort: []containertypes.SortOption{{Key: "size", Desc: true}},
+	})
+	assert.ErrorContains(t, err, "size")
+
+	_, err = d.Containers(context.Background(), &containertypes.ListOptions{
+		All:  true,
+		Size: true,
+		Sort: []containertypes.SortOption{{Key: "size", Desc: true}},
+	})
+	assert.NilError(t, err)
+}
+
+func TestPageToken(t *testing.T) {
+	db, err := container.NewViewDB()
+	assert.NilError(t, err)
+	d := &Daemon{
+		containersReplica: db,
+	}
+
+	num := 5 + rand.Intn(20)
+	containers := make([]*container.Container, num)
+	for i := range num {
+		name := fmt.Sprintf("cont-%d", i)
+		containers[i] = setupContainerWithName(t, name, d)
+		containers[i].Created = time.Now().Add(time.Duration(i) * time.Second)
+		d.containersReplica.Save(containers[i])
+	}
+
+	opts := containertypes.ListOptions{
+		All:   true,
+		Limit: 3,
+		Sort:  []containertypes.SortOption{{Key: "created", Desc: true}},
+	}
+
+	seen := map[string]bool{}
+	for page := 0; page <= num; page++ {
+		containerList, err := d.Containers(context.Background(), &opts)
+		assert.NilError(t, err)
+		if len(containerList) == 0 {
+			break
+		}
+		for _, ctr := range containerList {
+			assert.Assert(t, !seen[ctr.ID])
+			seen[ctr.ID] = true
+		}
+		last := containerList[len(containerList)-1]
+		token, err := d.EncodeNextPageToken(&opts, last)
+		assert.NilError(t, err)
+		opts.PageToken = token
+	}
+
+	assert.Assert(t, is.Len(seen, num))
+}
+
+func TestPageTokenSurvivesAnchorRemoval(t *testing.T) {
+	db, err := container.NewViewDB()
+	assert.NilError(t, err)
+	d := &Daemon{
+		containersReplica: db,
+	}
+
+	num := 5
+	containers := make([]*container.Container, num)
+	for i := range num {
+		name := fmt.Sprintf("churn-%d", i)
+		containers[i] = setupContainerWithName(t, name, d)
+		containers[i].Created = time.Now().Add(time.Duration(i) * time.Second)
+		d.containersReplica.Save(containers[i])
+	}
+
+	opts := containertypes.ListOptions{
+		All:   true,
+		Limit: 3,
+		Sort:  []containertypes.SortOption{{Key: "created", Desc: true}},
+	}
+	firstPage, err := d.Containers(context.Background(), &opts)
+	assert.NilError(t, err)
+	assert.Assert(t, is.Len(firstPage, 3))
+	anchor := firstPage[len(firstPage)-1]
+
+	token, err := d.EncodeNextPageToken(&opts, anchor)
+	assert.NilError(t, err)
+	opts.PageToken = token
+
+	// the anchor the token resumes after is removed between pages, as can
+	// happen on a busy host; resume must still find its way to the rest of
+	// the list instead of exact-matching nothing and returning an empty
+	// page with no further token.
+	for _, c := range containers {
+		if c.ID == anchor.ID {
+			assert.NilError(t, d.containersReplica.Delete(c))
+		}
+	}
+
+	secondPage, err := d.Containers(context.Background(), &opts)
+	assert.NilError(t, err)
+	assert.Assert(t, is.Len(secondPage, 2))
+	for _, ctr := range secondPage {
+		assert.Assert(t, ctr.ID != anchor.ID)
+	}
+}
+
+func TestPageTokenVersionGating(t *testing.T) {
+	db, err := container.NewViewDB()
+	assert.NilError(t, err)
+	d := &Daemon{
+		containersReplica: db,
+	}
+
+	num := 5
+	containers := make([]*container.Container, num)
+	for i := range num {
+		name := fmt.Sprintf("cont-%d", i)
+		containers[i] = setupContainerWithName(t, name, d)
+		containers[i].Created = time.Now().Add(time.Duration(i) * time.Second)
+		d.containersReplica.Save(containers[i])
+	}
+
+	opts := containertypes.ListOptions{
+		All:   true,
+		Limit: 3,
+		Sort:  []containertypes.SortOption{{Key: "created", Desc: true}},
+	}
+	ctx := httputils.WithVersion(context.Background(), sortMinVersion)
+	firstPage, err := d.Containers(ctx, &opts)
+	assert.NilError(t, err)
+	assert.Assert(t, is.Len(firstPage, 3))
+	token, err := d.EncodeNextPageToken(&opts, firstPage[len(firstPage)-1])
+	assert.NilError(t, err)
+
+	// an old API version never asked for page_token pagination, so a token
+	// it couldn't have produced itself must not be honored either.
+	oldCtx := httputils.WithVersion(context.Background(), "1.44")
+	opts.PageToken = token
+	containerList, err := d.Containers(oldCtx, &opts)
+	assert.NilError(t, err)
+	assert.Assert(t, is.Len(containerList, 3))
+	assert.Equal(t, containerList[0].ID, firstPage[0].ID)
+}
+
+func TestAnnotationFilter(t *testing.T) {
+	db, err := container.NewViewDB()
+	assert.NilError(t, err)
+	d := &Daemon{
+		containersReplica: db,
+	}
+
+	one := setupContainerWithName(t, "ann-1", d)
+	one.HostConfig.Annotations = map[string]string{"io.kubernetes.docker.type": "container", "foo": "bar"}
+	two := setupContainerWithName(t, "ann-2", d)
+	two.HostConfig.Annotations = map[string]string{"io.kubernetes.docker.type": "sandbox"}
+	three := setupContainerWithName(t, "ann-3", d)
+	d.containersReplica.Save(one)
+	d.containersReplica.Save(two)
+	d.containersReplica.Save(three)
+
+	containerList, err := d.Containers(context.Background(), &containertypes.ListOptions{
+		Filters: filters.NewArgs(filters.Arg("annotation", "io.kubernetes.docker.type=container")),
+	})
+	assert.NilError(t, err)
+	assert.Assert(t, is.Len(containerList, 1))
+	assert.Assert(t, containerListContainsName(containerList, one.Name))
+
+	// key-only form matches any value
+	containerList, err = d.Containers(context.Background(), &containertypes.ListOptions{
+		Filters: filters.NewArgs(filters.Arg("annotation", "io.kubernetes.docker.type")),
+	})
+	assert.NilError(t, err)
+	assert.Assert(t, is.Len(containerList, 2))
+	assert.Assert(t, containerListContainsName(containerList, one.Name))
+	assert.Assert(t, containerListContainsName(containerList, two.Name))
 }
@@ -45,6 +45,142 @@ func TestList(t *testing.T) {
 	}
 }
 
+func TestListPagination(t *testing.T) {
+	ctx := setupTest(t)
+	apiClient := request.NewAPIClient(t, client.WithVersion("1.48"))
+
+	num := 5 + rand.Intn(20)
+	ids := make([]string, num)
+	for i := range num {
+		id := container.Create(ctx, t, apiClient)
+		defer container.Remove(ctx, t, apiClient, id, containertypes.RemoveOptions{Force: true})
+		ids[i] = id
+	}
+
+	seen := map[string]bool{}
+	var all []string
+	opts := containertypes.ListOptions{
+		All:   true,
+		Limit: 3,
+		Sort:  []containertypes.SortOption{{Key: "created", Desc: true}},
+	}
+	for page := 0; page <= num; page++ { // bounded: a stuck cursor must not hang the test
+		containerList, nextToken, err := apiClient.ContainerListPage(ctx, opts)
+		assert.NilError(t, err)
+		for _, ctr := range containerList {
+			assert.Assert(t, !seen[ctr.ID], "container %s returned twice", ctr.ID)
+			seen[ctr.ID] = true
+			all = append(all, ctr.ID)
+		}
+		if nextToken == "" {
+			break
+		}
+		opts.PageToken = nextToken
+	}
+
+	assert.Assert(t, is.Len(all, num))
+	for _, id := range ids {
+		assert.Assert(t, seen[id], "missing container %s", id)
+	}
+}
+
+func TestListSort(t *testing.T) {
+	ctx := setupTest(t)
+
+	testcases := []struct {
+		apiVersion  string
+		expectOrder bool
+	}{
+		{apiVersion: "1.44", expectOrder: false},
+		{apiVersion: "1.48", expectOrder: true},
+	}
+
+	for _, tc := range testcases {
+		t.Run(fmt.Sprintf("run with version v%s", tc.apiVersion), func(t *testing.T) {
+			apiClient := request.NewAPIClient(t, client.WithVersion(tc.apiVersion))
+
+			names := []string{"c-sort-3", "c-sort-1", "c-sort-2"}
+			ids := make([]string, len(names))
+			for i, name := range names {
+				id := container.Create(ctx, t, apiClient, container.WithName(name))
+				defer container.Remove(ctx, t, apiClient, id, containertypes.RemoveOptions{Force: true})
+				ids[i] = id
+			}
+
+			containerList, err := apiClient.ContainerList(ctx, containertypes.ListOptions{
+				All:  true,
+				Sort: []containertypes.SortOption{{Key: "name"}},
+			})
+			assert.NilError(t, err)
+
+			var got []string
+			for _, ctr := range containerList {
+				for _, id := range ids {
+					if ctr.ID == id {
+						got = append(got, ctr.ID)
+					}
+				}
+			}
+
+			if !tc.expectOrder {
+				// Pre-1.48 daemons don't understand "sort"; all we can
+				// assert is that nothing broke.
+				assert.Assert(t, is.Len(got, len(ids)))
+				return
+			}
+
+			want := []string{ids[1], ids[2], ids[0]} // c-sort-1, c-sort-2, c-sort-3
+			assert.Check(t, is.DeepEqual(got, want))
+		})
+	}
+}
+
+func TestListFilterAnnotation(t *testing.T) {
+	ctx := setupTest(t)
+
+	testcases := []struct {
+		apiVersion   string
+		filterValue  string
+		expectFilter bool
+	}{
+		{apiVersion: "1.44", filterValue: "io.kubernetes.docker.type=container", expectFilter: false},
+		{apiVersion: "1.46", filterValue: "io.kubernetes.docker.type=container", expectFilter: true},
+		{apiVersion: "1.46", filterValue: "io.kubernetes.docker.type", expectFilter: true},
+	}
+
+	for _, tc := range testcases {
+		t.Run(fmt.Sprintf("run with version v%s filter %s", tc.apiVersion, tc.filterValue), func(t *testing.T) {
+			apiClient := request.NewAPIClient(t, client.WithVersion(tc.apiVersion))
+
+			wantID := container.Create(ctx, t, apiClient, container.WithAnnotations(map[string]string{
+				"io.kubernetes.docker.type": "container",
+			}))
+			defer container.Remove(ctx, t, apiClient, wantID, containertypes.RemoveOptions{Force: true})
+
+			otherID := container.Create(ctx, t, apiClient, container.WithAnnotations(map[string]string{
+				"io.kubernetes.docker.type": "sandbox",
+			}))
+			defer container.Remove(ctx, t, apiClient, otherID, containertypes.RemoveOptions{Force: true})
+
+			containerList, err := apiClient.ContainerList(ctx, containertypes.ListOptions{
+				All:     true,
+				Filters: filters.NewArgs(filters.Arg("annotation", tc.filterValue)),
+			})
+			assert.NilError(t, err)
+
+			if !tc.expectFilter {
+				// Below API 1.46 the daemon ignores the annotation filter
+				// rather than rejecting it, so both containers come back.
+				assert.Assert(t, is.Len(containerList, 2))
+				return
+			}
+
+			assert.Assert(t, is.Len(containerList, 1))
+			assert.Equal(t, containerList[0].ID, wantID)
+		})
+	}
+}
+
 func TestListAnnotations(t *testing.T) {
 	ctx := setupTest(t)
 
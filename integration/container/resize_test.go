@@ -52,3 +52,72 @@ func TestResizeWhenContainerNotStarted(t *testing.T) {
 	assert.Check(t, is.ErrorType(err, errdefs.IsConflict))
 	assert.Check(t, is.ErrorContains(err, "is not running"))
 }
+
+func TestExecResize(t *testing.T) {
+	ctx := setupTest(t)
+	apiClient := testEnv.APIClient()
+
+	cID := container.Run(ctx, t, apiClient, container.WithTty(true))
+
+	execResp, err := apiClient.ContainerExecCreate(ctx, cID, types.ExecConfig{
+		Cmd: []string{"sh"},
+		Tty: true,
+	})
+	assert.NilError(t, err)
+
+	attachResp, err := apiClient.ContainerExecAttach(ctx, execResp.ID, types.ExecStartCheck{Tty: true})
+	assert.NilError(t, err)
+	defer attachResp.Close()
+
+	err = apiClient.ContainerExecResize(ctx, execResp.ID, types.ResizeOptions{
+		Height: 40,
+		Width:  40,
+	})
+	assert.NilError(t, err)
+}
+
+func TestExecResizeWithInvalidSize(t *testing.T) {
+	skip.If(t, versions.LessThan(testEnv.DaemonAPIVersion(), "1.32"), "broken in earlier versions")
+	ctx := setupTest(t)
+	apiClient := testEnv.APIClient()
+
+	cID := container.Run(ctx, t, apiClient, container.WithTty(true))
+
+	execResp, err := apiClient.ContainerExecCreate(ctx, cID, types.ExecConfig{
+		Cmd: []string{"sh"},
+		Tty: true,
+	})
+	assert.NilError(t, err)
+
+	attachResp, err := apiClient.ContainerExecAttach(ctx, execResp.ID, types.ExecStartCheck{Tty: true})
+	assert.NilError(t, err)
+	defer attachResp.Close()
+
+	// Manually creating a request here, as the APIClient would invalidate
+	// these values before they're sent.
+	res, _, err := req.Post(ctx, "/exec/"+execResp.ID+"/resize?h=foo&w=bar")
+	assert.NilError(t, err)
+	assert.Check(t, is.DeepEqual(http.StatusBadRequest, res.StatusCode))
+}
+
+func TestExecResizeWhenNotStarted(t *testing.T) {
+	ctx := setupTest(t)
+	apiClient := testEnv.APIClient()
+
+	cID := container.Run(ctx, t, apiClient, container.WithTty(true))
+
+	execResp, err := apiClient.ContainerExecCreate(ctx, cID, types.ExecConfig{
+		Cmd: []string{"sh"},
+		Tty: true,
+	})
+	assert.NilError(t, err)
+
+	// Resizing before the exec has been started (attached/started) should
+	// be rejected the same way resizing a not-yet-started container is.
+	err = apiClient.ContainerExecResize(ctx, execResp.ID, types.ResizeOptions{
+		Height: 40,
+		Width:  40,
+	})
+	assert.Check(t, is.ErrorType(err, errdefs.IsConflict))
+	assert.Check(t, is.ErrorContains(err, "is not running"))
+}
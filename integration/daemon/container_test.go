@@ -0,0 +1,118 @@
+package daemon // import "github.com/docker/docker/integration/daemon"
+
+import (
+	"context"
+	"testing"
+
+	containertypes "github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/integration/internal/container"
+	"github.com/docker/docker/testutil/daemon"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+	"gotest.tools/v3/skip"
+)
+
+// TestContainerListSortMultiKey covers sorting by more than one key, which
+// integration/container/list_test.go's TestListSort doesn't exercise: a
+// dedicated daemon here lets the test control exactly which containers
+// exist, so ties on the first key are guaranteed rather than incidental.
+func TestContainerListSortMultiKey(t *testing.T) {
+	skip.If(t, testEnv.IsRemoteDaemon)
+	ctx := context.Background()
+
+	d := daemon.New(t)
+	d.StartWithBusybox(ctx, t)
+	defer d.Stop(t)
+
+	apiClient := d.NewClientT(t)
+	defer apiClient.Close()
+
+	// two containers share a label value, so the tie must be broken by
+	// the second sort key (name) rather than being left in arbitrary order.
+	names := []string{"multi-sort-b", "multi-sort-a", "multi-sort-c"}
+	tiers := []string{"prod", "prod", "dev"}
+	ids := make([]string, len(names))
+	for i, name := range names {
+		ids[i] = container.Run(ctx, t, apiClient,
+			container.WithName(name),
+			container.WithLabels(map[string]string{"tier": tiers[i]}),
+		)
+		defer container.Remove(ctx, t, apiClient, ids[i], containertypes.RemoveOptions{Force: true})
+	}
+
+	containerList, err := apiClient.ContainerList(ctx, containertypes.ListOptions{
+		All: true,
+		Sort: []containertypes.SortOption{
+			{Key: "label:tier"},
+			{Key: "name"},
+		},
+	})
+	assert.NilError(t, err)
+
+	var got []string
+	for _, ctr := range containerList {
+		for _, id := range ids {
+			if ctr.ID == id {
+				got = append(got, ctr.ID)
+			}
+		}
+	}
+
+	// dev < prod, and within "prod" the names break the tie: a, then b.
+	want := []string{ids[2], ids[1], ids[0]}
+	assert.Check(t, is.DeepEqual(got, want))
+}
+
+// TestContainerListSortStableUnderTies covers the request's explicit ask
+// for "stable ordering under ties": containers with identical Sort values
+// across every requested key must still come back in the same order on
+// repeated calls, broken by the implicit ID tiebreaker.
+func TestContainerListSortStableUnderTies(t *testing.T) {
+	skip.If(t, testEnv.IsRemoteDaemon)
+	ctx := context.Background()
+
+	d := daemon.New(t)
+	d.StartWithBusybox(ctx, t)
+	defer d.Stop(t)
+
+	apiClient := d.NewClientT(t)
+	defer apiClient.Close()
+
+	var ids []string
+	for i := 0; i < 4; i++ {
+		id := container.Run(ctx, t, apiClient,
+			container.WithLabels(map[string]string{"tier": "prod"}),
+		)
+		defer container.Remove(ctx, t, apiClient, id, containertypes.RemoveOptions{Force: true})
+		ids = append(ids, id)
+	}
+
+	opts := containertypes.ListOptions{
+		All:  true,
+		Sort: []containertypes.SortOption{{Key: "label:tier"}},
+	}
+
+	first, err := apiClient.ContainerList(ctx, opts)
+	assert.NilError(t, err)
+	second, err := apiClient.ContainerList(ctx, opts)
+	assert.NilError(t, err)
+
+	firstOrder := idsIn(first, ids)
+	secondOrder := idsIn(second, ids)
+	assert.Assert(t, is.Len(firstOrder, len(ids)))
+	assert.Check(t, is.DeepEqual(firstOrder, secondOrder), "tie-broken order must be stable across calls")
+}
+
+func idsIn(list []containertypes.Summary, ids []string) []string {
+	want := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		want[id] = true
+	}
+	var got []string
+	for _, ctr := range list {
+		if want[ctr.ID] {
+			got = append(got, ctr.ID)
+		}
+	}
+	return got
+}